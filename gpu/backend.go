@@ -23,16 +23,33 @@ type Backend interface {
 	NilTexture() Texture
 	NewFramebuffer() Framebuffer
 	NewBuffer(typ BufferType, data []byte) Buffer
+	// NewStreamingBuffer creates a Buffer of size bytes intended for
+	// repeated per-frame updates via Buffer.Upload, rather than
+	// replacement via NewBuffer. Backends are free to implement it with
+	// persistent-mapped memory or a ring of orphaned buffers, as long as
+	// writes don't stall the GPU.
+	NewStreamingBuffer(typ BufferType, size int, usage BufferUsage) Buffer
 	NewProgram(vertexShader, fragmentShader ShaderSources) (Program, error)
 	NewInputLayout(vertexShader ShaderSources, layout []InputDesc) (InputLayout, error)
 
 	DepthFunc(f DepthFunc)
 	ClearColor(r, g, b, a float32)
+	// ClearColorAttachment is like ClearColor but targets a single
+	// color attachment of a multiple-render-target Framebuffer. Only
+	// valid when FeatureMRT is supported.
+	ClearColorAttachment(index int, r, g, b, a float32)
 	ClearDepth(d float32)
 	Clear(buffers BufferAttachments)
 	Viewport(x, y, width, height int)
 	DrawArrays(mode DrawMode, off, count int)
 	DrawElements(mode DrawMode, off, count int)
+	// DrawArraysInstanced is DrawArrays repeated instances times, with
+	// per-instance attributes (InputDesc.Divisor > 0) advancing once per
+	// instance rather than once per vertex. Requires FeatureInstancing.
+	DrawArraysInstanced(mode DrawMode, off, count, instances int)
+	// DrawElementsInstanced is DrawElements repeated instances times, see
+	// DrawArraysInstanced. Requires FeatureInstancing.
+	DrawElementsInstanced(mode DrawMode, off, count, instances int)
 	SetBlend(enable bool)
 	SetDepthTest(enable bool)
 	DepthMask(mask bool)
@@ -40,10 +57,25 @@ type Backend interface {
 }
 
 type ShaderSources struct {
-	GLES2    string
-	HLSL     []byte
+	GLES2 string
+	HLSL  []byte
+	// WGSL holds the WebGPU Shading Language source for a future
+	// wgpu-native Backend implementation. No such Backend exists yet in
+	// this tree: WGSL is plumbed through ShaderSources so that shader
+	// cross-compilation tooling has somewhere to put its output ahead of
+	// the pipeline/render-pass work the backend itself still needs.
+	//
+	// This field alone does not deliver a Vulkan/WebGPU backend: the
+	// wgpu-native bindings, the cross-compilation step, and the
+	// PipelineDescriptor/RenderPipeline cache mapping the immediate-mode
+	// calls above onto RenderPassEncoder are still unwritten.
+	WGSL     []byte
 	Uniforms []UniformLocation
 	Inputs   []InputLocation
+	// FragmentOutputs describes the color outputs a fragment shader
+	// writes, in attachment order. Only meaningful when FeatureMRT is
+	// supported and more than one color attachment is bound.
+	FragmentOutputs []OutputLocation
 }
 
 type UniformLocation struct {
@@ -65,12 +97,26 @@ type InputLocation struct {
 	Size int
 }
 
+// OutputLocation describes a fragment shader color output, used to
+// translate a shader to the per-attachment bindings required by
+// multiple render targets (gl_FragData[i] or SV_TargetN).
+type OutputLocation struct {
+	Name  string
+	Index int
+}
+
 // InputDesc describes a vertex attribute as laid out in a Buffer.
 type InputDesc struct {
 	Type DataType
 	Size int
 
 	Offset int
+
+	// Divisor is the number of instances drawn between successive
+	// updates of this attribute. Zero means the attribute advances once
+	// per vertex as usual; a non-zero Divisor requires FeatureInstancing
+	// and is only meaningful with DrawArraysInstanced/DrawElementsInstanced.
+	Divisor int
 }
 
 // InputLayout is the backend specific representation of the mapping
@@ -100,6 +146,13 @@ type Features uint
 type Caps struct {
 	Features       Features
 	MaxTextureSize int
+	// MaxColorAttachments is the number of color attachments that can
+	// be bound to a Framebuffer at once. It is 1 unless
+	// FeatureMRT is supported.
+	MaxColorAttachments int
+	// MaxVertexAttribs is the number of vertex attribute slots an
+	// InputLayout can use at once.
+	MaxVertexAttribs int
 }
 
 type Program interface {
@@ -118,11 +171,23 @@ type Buffer interface {
 	BindVertex(stride, offset int)
 	Bind()
 	Release()
+	// Upload writes data into the buffer starting at offset, without
+	// reallocating it. Only valid on buffers created with
+	// Backend.NewStreamingBuffer.
+	Upload(offset int, data []byte)
 }
 
+// BufferUsage hints at how a streaming Buffer will be written to, so the
+// backend can pick an appropriate update strategy.
+type BufferUsage uint8
+
 type Framebuffer interface {
 	Bind()
 	BindTexture(t Texture)
+	// BindTextures binds multiple color attachments for a single draw
+	// call, for backends that support FeatureMRT. attachments[i] becomes
+	// color attachment i.
+	BindTextures(attachments []Texture)
 	Invalidate()
 	Release()
 	IsComplete() error
@@ -161,6 +226,18 @@ const (
 	BufferTypeData
 )
 
+const (
+	// BufferUsageStatic is for buffers written once at creation and
+	// never updated.
+	BufferUsageStatic BufferUsage = iota
+	// BufferUsageDynamic is for buffers updated occasionally, such as on
+	// content changes.
+	BufferUsageDynamic
+	// BufferUsageStream is for buffers updated every frame, such as
+	// per-frame vertex data.
+	BufferUsageStream
+)
+
 const (
 	TextureFormatSRGB TextureFormat = iota
 	TextureFormatFloat
@@ -172,7 +249,14 @@ const (
 )
 
 const (
-	FeatureTimers Features = iota
+	FeatureTimers Features = 1 << iota
+	// FeatureMRT indicates support for binding multiple color
+	// attachments to a Framebuffer and rendering to them in a single
+	// draw call.
+	FeatureMRT
+	// FeatureInstancing indicates support for DrawArraysInstanced and
+	// DrawElementsInstanced, and for InputDesc.Divisor.
+	FeatureInstancing
 )
 
 const (
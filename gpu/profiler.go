@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package gpu
+
+import "time"
+
+// Profiler attributes GPU time to named scopes (upload, stencil, cover,
+// user draws, ...) by driving a Backend's Timer objects through a scoped
+// tree. To avoid stalling on Duration(), it keeps a small ring of frames
+// in flight and only reads back results from a frame once the ring has
+// come round to it again; a frame whose timers are not ready by then is
+// dropped.
+type Profiler struct {
+	b     Backend
+	ring  []profFrame
+	frame int
+	stack []*profScope
+}
+
+// FrameProfile is the result of a single profiled frame: a tree of named
+// durations mirroring the Begin/End scopes recorded during the frame.
+// A frame has a single root: a later top-level Begin in the same frame
+// replaces the earlier root, whose scopes are then not in the profile.
+type FrameProfile struct {
+	Label    string
+	Duration time.Duration
+	Children []FrameProfile
+}
+
+type profScope struct {
+	label    string
+	timer    Timer
+	parent   *profScope
+	children []*profScope
+}
+
+type profFrame struct {
+	root    *profScope
+	timers  []Timer
+	pending bool
+}
+
+// NewProfiler creates a Profiler driving timers on b. frames is the size
+// of the ring of in-flight frames; a larger ring tolerates more latency
+// between a timer's End and its Duration becoming available without
+// stalling the GPU. frames must be at least 1.
+func NewProfiler(b Backend, frames int) *Profiler {
+	if frames <= 0 {
+		panic("gpu: NewProfiler frames must be positive")
+	}
+	return &Profiler{
+		b:    b,
+		ring: make([]profFrame, frames),
+	}
+}
+
+// Frame finalizes the current frame's scopes and returns the FrameProfile
+// for the oldest frame in the ring, if all of its timers are ready. Either
+// way, the oldest frame's Timers are released: the ring only holds
+// len(ring) frames' worth of in-flight query objects, so its slot must be
+// clear before Begin reuses it for a new frame.
+func (p *Profiler) Frame() (FrameProfile, bool) {
+	cur := &p.ring[p.frame]
+	cur.pending = true
+	p.frame = (p.frame + 1) % len(p.ring)
+	next := &p.ring[p.frame]
+	if !next.pending || next.root == nil {
+		return FrameProfile{}, false
+	}
+	prof, ok := buildProfile(next.root)
+	releaseFrame(next)
+	if !ok {
+		return FrameProfile{}, false
+	}
+	return prof, true
+}
+
+// releaseFrame releases every Timer recorded for f and clears it for
+// reuse by a future frame.
+func releaseFrame(f *profFrame) {
+	for _, t := range f.timers {
+		t.Release()
+	}
+	f.timers = nil
+	f.root = nil
+	f.pending = false
+}
+
+// Begin starts a named timer scope, nested under the current scope if
+// any. Every Begin must be matched by a corresponding End.
+func (p *Profiler) Begin(label string) {
+	s := &profScope{label: label, timer: p.b.NewTimer()}
+	cur := &p.ring[p.frame]
+	if len(p.stack) == 0 {
+		cur.root = s
+	} else {
+		parent := p.stack[len(p.stack)-1]
+		parent.children = append(parent.children, s)
+		s.parent = parent
+	}
+	cur.timers = append(cur.timers, s.timer)
+	p.stack = append(p.stack, s)
+	s.timer.Begin()
+}
+
+// End closes the most recently opened scope.
+func (p *Profiler) End() {
+	n := len(p.stack) - 1
+	s := p.stack[n]
+	p.stack = p.stack[:n]
+	s.timer.End()
+}
+
+func buildProfile(s *profScope) (FrameProfile, bool) {
+	d, ok := s.timer.Duration()
+	if !ok {
+		return FrameProfile{}, false
+	}
+	prof := FrameProfile{Label: s.label, Duration: d}
+	for _, c := range s.children {
+		cp, ok := buildProfile(c)
+		if !ok {
+			return FrameProfile{}, false
+		}
+		prof.Children = append(prof.Children, cp)
+	}
+	return prof, true
+}
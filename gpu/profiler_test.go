@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+package gpu
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeTimer struct {
+	done     bool
+	released bool
+}
+
+func (t *fakeTimer) Begin() {}
+func (t *fakeTimer) End()   { t.done = true }
+func (t *fakeTimer) Duration() (time.Duration, bool) {
+	if !t.done {
+		return 0, false
+	}
+	return time.Millisecond, true
+}
+func (t *fakeTimer) Release() { t.released = true }
+
+type fakeBackend struct {
+	timers []*fakeTimer
+}
+
+func (b *fakeBackend) NewTimer() Timer {
+	t := &fakeTimer{}
+	b.timers = append(b.timers, t)
+	return t
+}
+
+func (b *fakeBackend) BeginFrame()                                            {}
+func (b *fakeBackend) EndFrame()                                              {}
+func (b *fakeBackend) Caps() Caps                                             { return Caps{} }
+func (b *fakeBackend) IsTimeContinuous() bool                                 { return true }
+func (b *fakeBackend) NewTexture(TextureFilter, TextureFilter) Texture        { return nil }
+func (b *fakeBackend) DefaultFramebuffer() Framebuffer                        { return nil }
+func (b *fakeBackend) NilTexture() Texture                                    { return nil }
+func (b *fakeBackend) NewFramebuffer() Framebuffer                            { return nil }
+func (b *fakeBackend) NewBuffer(BufferType, []byte) Buffer                    { return nil }
+func (b *fakeBackend) NewStreamingBuffer(BufferType, int, BufferUsage) Buffer { return nil }
+func (b *fakeBackend) NewProgram(_, _ ShaderSources) (Program, error)         { return nil, nil }
+func (b *fakeBackend) NewInputLayout(_ ShaderSources, _ []InputDesc) (InputLayout, error) {
+	return nil, nil
+}
+func (b *fakeBackend) DepthFunc(DepthFunc)                                          {}
+func (b *fakeBackend) ClearColor(float32, float32, float32, float32)                {}
+func (b *fakeBackend) ClearColorAttachment(int, float32, float32, float32, float32) {}
+func (b *fakeBackend) ClearDepth(float32)                                           {}
+func (b *fakeBackend) Clear(BufferAttachments)                                      {}
+func (b *fakeBackend) Viewport(int, int, int, int)                                  {}
+func (b *fakeBackend) DrawArrays(DrawMode, int, int)                                {}
+func (b *fakeBackend) DrawElements(DrawMode, int, int)                              {}
+func (b *fakeBackend) DrawArraysInstanced(DrawMode, int, int, int)                  {}
+func (b *fakeBackend) DrawElementsInstanced(DrawMode, int, int, int)                {}
+func (b *fakeBackend) SetBlend(bool)                                                {}
+func (b *fakeBackend) SetDepthTest(bool)                                            {}
+func (b *fakeBackend) DepthMask(bool)                                               {}
+func (b *fakeBackend) BlendFunc(BlendFactor, BlendFactor)                           {}
+
+func TestNewProfilerPanicsOnNonPositiveFrames(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("NewProfiler(%d) did not panic", n)
+				}
+			}()
+			NewProfiler(&fakeBackend{}, n)
+		}()
+	}
+}
+
+// TestProfilerReleasesEvictedTimers checks that as the ring cycles, every
+// timer but the one still in flight for the current frame is released.
+func TestProfilerReleasesEvictedTimers(t *testing.T) {
+	b := &fakeBackend{}
+	p := NewProfiler(b, 2)
+	for i := 0; i < 4; i++ {
+		p.Begin("root")
+		p.End()
+		p.Frame()
+	}
+	for i, tm := range b.timers {
+		want := i < len(b.timers)-1
+		if tm.released != want {
+			t.Fatalf("timer %d: released=%v, want %v", i, tm.released, want)
+		}
+	}
+}
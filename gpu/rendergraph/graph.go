@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: Unlicense OR MIT
+
+// Package rendergraph schedules a frame's rendering as a directed graph
+// of passes between Gio's op processor and a raw gpu.Backend, so that
+// transient framebuffers can be aliased and passes whose output is never
+// consumed can be skipped.
+package rendergraph
+
+import "gioui.org/gpu"
+
+// ResourceHandle names a transient resource (a Framebuffer or Texture)
+// declared by a pass. It is only valid for the Graph that created it.
+type ResourceHandle int
+
+// Encoder is the subset of a gpu.Backend available to a Pass's Exec
+// function, scoped to the resources the pass declared as writes.
+type Encoder interface {
+	Clear(buffers gpu.BufferAttachments)
+	Viewport(x, y, width, height int)
+	DrawArrays(mode gpu.DrawMode, off, count int)
+	DrawElements(mode gpu.DrawMode, off, count int)
+	SetBlend(enable bool)
+	SetDepthTest(enable bool)
+	BlendFunc(sfactor, dfactor gpu.BlendFactor)
+}
+
+// Pass is one node of the graph: a named unit of work that reads some
+// resources and writes others. Before exec is called, the Framebuffer of
+// each of the pass's writes is bound in turn, so the last write's is the
+// one bound; exec receives the Encoder and the Textures backing its
+// reads, in the same order as the reads passed to AddPass.
+type Pass struct {
+	name   string
+	reads  []ResourceHandle
+	writes []ResourceHandle
+	exec   func(cmd Encoder, reads []gpu.Texture)
+}
+
+// resource tracks the declared size/format of a transient resource and
+// the range of passes that touch it, used to decide aliasing and
+// invalidation. tex is the actual pixel storage; fbo is a Framebuffer
+// with tex bound as its color attachment, so writers can Bind it and
+// readers can sample tex directly.
+type resource struct {
+	desc       ResourceDesc
+	firstWrite int
+	lastRead   int
+	fbo        gpu.Framebuffer
+	tex        gpu.Texture
+}
+
+// ResourceDesc describes a transient resource a Graph should allocate.
+// Two resources with equal ResourceDesc are eligible to share a backing
+// Framebuffer when their lifetimes don't overlap.
+type ResourceDesc struct {
+	Width, Height int
+	Format        gpu.TextureFormat
+}
+
+// Graph accumulates passes for a single frame and compiles them into a
+// linear command stream against a gpu.Backend.
+type Graph struct {
+	b         gpu.Backend
+	passes    []Pass
+	resources []resource
+	outputs   map[ResourceHandle]bool
+}
+
+// New creates a Graph that allocates transient resources from b.
+func New(b gpu.Backend) *Graph {
+	return &Graph{b: b, outputs: make(map[ResourceHandle]bool)}
+}
+
+// NewResource declares a transient resource and returns a handle to it.
+func (g *Graph) NewResource(desc ResourceDesc) ResourceHandle {
+	h := ResourceHandle(len(g.resources))
+	g.resources = append(g.resources, resource{desc: desc, firstWrite: -1, lastRead: -1})
+	return h
+}
+
+// AddPass records a pass that reads the resources in reads and writes
+// the resources in writes. exec is invoked during Compile for passes
+// that survive culling, with the Texture backing each entry of reads
+// passed alongside the Encoder.
+func (g *Graph) AddPass(name string, reads, writes []ResourceHandle, exec func(cmd Encoder, reads []gpu.Texture)) {
+	g.passes = append(g.passes, Pass{name: name, reads: reads, writes: writes, exec: exec})
+}
+
+// MarkOutput marks a resource as externally observed (for example, the
+// default framebuffer), so that passes producing it survive culling even
+// if no later pass reads it.
+func (g *Graph) MarkOutput(h ResourceHandle) {
+	g.outputs[h] = true
+}
+
+// Compile culls passes whose writes are never read nor marked as
+// outputs, aliases non-overlapping resources to the same backing
+// Framebuffer, and runs the remaining passes in order, invalidating
+// framebuffer contents between passes that don't need them preserved.
+func (g *Graph) Compile() {
+	live := g.cull()
+	g.computeLifetimes(live)
+	g.alias()
+	for _, idx := range live {
+		p := g.passes[idx]
+		for _, h := range p.writes {
+			fbo := g.resources[h].fbo
+			if g.resources[h].lastRead < idx {
+				// Nothing after this pass reads the previous contents,
+				// so the tile memory backing it need not be preserved.
+				fbo.Invalidate()
+			}
+			fbo.Bind()
+		}
+		reads := make([]gpu.Texture, len(p.reads))
+		for i, h := range p.reads {
+			reads[i] = g.resources[h].tex
+		}
+		p.exec(g.b, reads)
+	}
+}
+
+// cull returns the indices, in original order, of passes that have no
+// writes or that transitively contribute to a MarkOutput'd resource or
+// to a resource read by a kept pass.
+func (g *Graph) cull() []int {
+	needed := make(map[ResourceHandle]bool, len(g.outputs))
+	for h := range g.outputs {
+		needed[h] = true
+	}
+	var live []int
+	for i := len(g.passes) - 1; i >= 0; i-- {
+		p := g.passes[i]
+		// A pass with no writes has no output for a later pass to
+		// consume, so the only way it's ever useful is the read itself
+		// (e.g. a final composite-to-screen pass): always keep it.
+		keep := len(p.writes) == 0
+		for _, h := range p.writes {
+			if needed[h] {
+				keep = true
+			}
+		}
+		if !keep {
+			continue
+		}
+		for _, h := range p.reads {
+			needed[h] = true
+		}
+		live = append([]int{i}, live...)
+	}
+	return live
+}
+
+func (g *Graph) computeLifetimes(live []int) {
+	for _, idx := range live {
+		p := g.passes[idx]
+		for _, h := range p.writes {
+			r := &g.resources[h]
+			if r.firstWrite == -1 {
+				r.firstWrite = idx
+			}
+		}
+		for _, h := range p.reads {
+			g.resources[h].lastRead = idx
+		}
+	}
+}
+
+// alias assigns a backing Texture and Framebuffer to every resource
+// touched by a live pass, reusing one from an earlier resource whose
+// lifetime has already ended (its lastRead precedes this resource's
+// firstWrite) and whose ResourceDesc matches.
+func (g *Graph) alias() {
+	var free []ResourceHandle
+	for h := range g.resources {
+		handle := ResourceHandle(h)
+		r := &g.resources[handle]
+		if r.firstWrite == -1 {
+			continue
+		}
+		reused := false
+		for i, f := range free {
+			cand := &g.resources[f]
+			// An output is observed after the frame, so its lifetime never
+			// ends; a resource that is never read lives until its write.
+			if g.outputs[f] {
+				continue
+			}
+			end := cand.lastRead
+			if end < cand.firstWrite {
+				end = cand.firstWrite
+			}
+			if cand.desc == r.desc && end < r.firstWrite {
+				r.fbo = cand.fbo
+				r.tex = cand.tex
+				free = append(free[:i], free[i+1:]...)
+				reused = true
+				break
+			}
+		}
+		if !reused {
+			tex := g.b.NewTexture(gpu.FilterLinear, gpu.FilterLinear)
+			tex.Resize(r.desc.Format, r.desc.Width, r.desc.Height)
+			fbo := g.b.NewFramebuffer()
+			fbo.BindTexture(tex)
+			r.tex = tex
+			r.fbo = fbo
+		}
+		free = append(free, handle)
+	}
+}